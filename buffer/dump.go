@@ -0,0 +1,85 @@
+package buffer
+
+import (
+    "fmt"
+    "os"
+    "sort"
+    "strings"
+    "unicode/utf8"
+)
+
+// String returns the buffer contents as written,
+// after translation to UTF-8 if an encoding is set.
+func (o *Output) String() string {
+    return string(o.buffer)
+}
+
+// Dump serializes the buffer and its state in a stable textual form.
+// C0 controls are shown in caret notation, C1 controls as \u00NN and
+// invalid UTF-8 bytes as \xNN, with a literal \ or ^ escaped by a
+// backslash, so different buffers never dump the same.
+func (o *Output) Dump() string {
+    var b strings.Builder
+    b.WriteString("modes:")
+    writeModes(&b, o.modes, "?")
+    writeModes(&b, o.ansiModes, "")
+    b.WriteString("\n")
+    fmt.Fprintf(&b, "sgr: %s\n", o.sgr.String())
+    fmt.Fprintf(&b, "margins: %d;%d\n", o.top, o.bottom)
+    fmt.Fprintf(&b, "cursor: %d\n", o.cursorStyle)
+    b.WriteString("--\n")
+    for i := 0; i < len(o.buffer); {
+        r, size := utf8.DecodeRune(o.buffer[i:])
+        switch {
+        case r == utf8.RuneError && size == 1:
+            fmt.Fprintf(&b, "\\x%02x", o.buffer[i])
+        case r == '\n':
+            b.WriteByte('\n')
+        case r < 0x20:
+            b.WriteByte('^')
+            b.WriteRune(r + '@')
+        case r == 0x7f:
+            b.WriteString("^?")
+        case r >= 0x80 && r <= 0x9f:
+            fmt.Fprintf(&b, "\\u%04x", r)
+        case r == '\\' || r == '^':
+            b.WriteByte('\\')
+            b.WriteRune(r)
+        default:
+            b.Write(o.buffer[i : i+size])
+        }
+        i += size
+    }
+    return b.String()
+}
+
+// writeModes writes modes sorted by number as " <prefix><mode>=<0|1>".
+func writeModes(b *strings.Builder, modes map[int]bool, prefix string) {
+    keys := make([]int, 0, len(modes))
+    for k := range modes {
+        keys = append(keys, k)
+    }
+    sort.Ints(keys)
+    for _, k := range keys {
+        v := 0
+        if modes[k] {
+            v = 1
+        }
+        fmt.Fprintf(b, " %s%d=%d", prefix, k, v)
+    }
+}
+
+// LoadSnapshot reads an expected Dump from a file.
+// Line endings are normalized so snapshots survive a CRLF checkout.
+func LoadSnapshot(path string) (string, error) {
+    data, err := os.ReadFile(path)
+    if err != nil {
+        return "", err
+    }
+    return strings.ReplaceAll(string(data), "\r\n", "\n"), nil
+}
+
+// WriteSnapshot writes the current Dump to a file.
+func (o *Output) WriteSnapshot(path string) error {
+    return os.WriteFile(path, []byte(o.Dump()), 0644)
+}
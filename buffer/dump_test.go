@@ -0,0 +1,67 @@
+package buffer
+
+import (
+    "flag"
+    "path/filepath"
+    "testing"
+)
+
+var update = flag.Bool("update", false, "rewrite snapshot files")
+
+func TestDumpSnapshots(t *testing.T) {
+    tests := []struct {
+        name string
+        in   string
+    }{
+        {"plain", "hello\r\nworld\r\n"},
+        {"modes", "\x1b[?2004h\x1b[?25l\x1b[4h\x1b[1;38;5;196m\x1b[2;10r\x1b[6 qtext\x7f"},
+        {"reset", "\x1b[?2004h\x1b[1m\x1bcdone"},
+        {"escapes", "a\xff\xc2\x9bb\xc2\n\\x41 ^[ \u009b\x1b[m\x9b"},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            o := NewOutputBuffer(0)
+            o.Resize(24, 80)
+            o.Write([]byte(tt.in))
+            path := filepath.Join("testdata", tt.name+".txt")
+            if *update {
+                if err := o.WriteSnapshot(path); err != nil {
+                    t.Fatal(err)
+                }
+            }
+            want, err := LoadSnapshot(path)
+            if err != nil {
+                t.Fatal(err)
+            }
+            if got := o.Dump(); got != want {
+                t.Errorf("Dump() =\n%s\nwant\n%s", got, want)
+            }
+        })
+    }
+}
+
+func TestDumpDistinct(t *testing.T) {
+    inputs := []string{
+        "a\xffb", "a\ufffdb", "a\\xffb",
+        "a\u009bb", "a\\u009bb", "a\x9bb",
+        "a\x1bb", "a^[b", "a\\^[b",
+    }
+    seen := map[string]string{}
+    for _, in := range inputs {
+        o := NewOutputBuffer(0)
+        o.Write([]byte(in))
+        d := o.Dump()
+        if prev, ok := seen[d]; ok {
+            t.Errorf("%q and %q dump the same:\n%s", prev, in, d)
+        }
+        seen[d] = in
+    }
+}
+
+func TestString(t *testing.T) {
+    o := NewOutputBuffer(64)
+    o.Write([]byte("a\x1b[1mb\n"))
+    if got, want := o.String(), "a\x1b[1mb\n"; got != want {
+        t.Errorf("String() = %q, want %q", got, want)
+    }
+}
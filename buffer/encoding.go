@@ -0,0 +1,45 @@
+package buffer
+
+import (
+    "fmt"
+    "strings"
+    "unicode/utf8"
+)
+
+// Encoding is the character encoding of data written to the buffer.
+type Encoding int
+
+const (
+    // EncodingUTF8 stores data as is
+    EncodingUTF8 Encoding = iota
+    // EncodingLatin1 translates ISO-8859-1 to UTF-8
+    EncodingLatin1
+)
+
+// ParseEncoding returns the encoding with the given name.
+func ParseEncoding(name string) (Encoding, error) {
+    switch strings.ToLower(name) {
+    case "utf-8", "utf8":
+        return EncodingUTF8, nil
+    case "iso-8859-1", "latin1", "latin-1":
+        return EncodingLatin1, nil
+    }
+    return EncodingUTF8, fmt.Errorf("unknown encoding %q", name)
+}
+
+// SetEncoding sets the encoding of data passed to Write.
+func (o *Output) SetEncoding(e Encoding) {
+    o.encoding = e
+}
+
+// decode translates b from the buffer encoding to UTF-8.
+func (o *Output) decode(b []byte) []byte {
+    if o.encoding != EncodingLatin1 {
+        return b
+    }
+    out := make([]byte, 0, len(b))
+    for _, c := range b {
+        out = utf8.AppendRune(out, rune(c))
+    }
+    return out
+}
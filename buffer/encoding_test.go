@@ -0,0 +1,42 @@
+package buffer
+
+import "testing"
+
+func TestParseEncoding(t *testing.T) {
+    tests := []struct {
+        name    string
+        want    Encoding
+        wantErr bool
+    }{
+        {"utf-8", EncodingUTF8, false},
+        {"UTF8", EncodingUTF8, false},
+        {"ISO-8859-1", EncodingLatin1, false},
+        {"latin1", EncodingLatin1, false},
+        {"shift-jis", EncodingUTF8, true},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            got, err := ParseEncoding(tt.name)
+            if (err != nil) != tt.wantErr {
+                t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+            }
+            if got != tt.want {
+                t.Errorf("encoding = %v, want %v", got, tt.want)
+            }
+        })
+    }
+}
+
+func TestLatin1(t *testing.T) {
+    r := &recorder{}
+    o := NewOutputBuffer(0)
+    o.SetHandler(r)
+    o.SetEncoding(EncodingLatin1)
+    o.Write([]byte("caf\xe9 \x1b]0;na\xefve\x07"))
+    if got, want := o.String(), "café \x1b]0;naïve\x07"; got != want {
+        t.Errorf("String() = %q, want %q", got, want)
+    }
+    if len(r.events) != 1 || r.events[0] != "title naïve" {
+        t.Errorf("events = %q", r.events)
+    }
+}
@@ -0,0 +1,22 @@
+package buffer
+
+// Handler receives events from sequences written to the buffer.
+// The host terminal implements it to react to them.
+type Handler interface {
+    // Bell is called when a BEL is received.
+    Bell()
+    // TitleChanged is called for OSC 0 and OSC 2.
+    TitleChanged(title string)
+    // ClipboardWrite is called for OSC 52 with the decoded data.
+    ClipboardWrite(selection string, data []byte)
+    // CwdChanged is called for OSC 7 with the reported URI.
+    CwdChanged(uri string)
+    // ResizeRequest is called for CSI 8 ; rows ; cols t.
+    ResizeRequest(rows, cols int)
+}
+
+// SetHandler sets the handler that receives buffer events.
+// A nil handler disables them.
+func (o *Output) SetHandler(h Handler) {
+    o.handler = h
+}
@@ -0,0 +1,44 @@
+package buffer
+
+import "time"
+
+// Line is a completed line of output.
+type Line struct {
+    // Text is the line without its line ending
+    Text string
+    // Time is when the line was completed
+    Time time.Time
+}
+
+// lineMark records where a completed line is in the buffer.
+type lineMark struct {
+    // start and end are offsets of the first byte and the line feed
+    start, end int
+    time       time.Time
+}
+
+// LineCount returns the number of completed lines.
+func (o *Output) LineCount() int {
+    return len(o.lines)
+}
+
+// Line returns the i-th completed line, oldest first.
+// A CR before the line feed is not part of the text.
+func (o *Output) Line(i int) Line {
+    m := o.lines[i]
+    end := m.end
+    if end > m.start && o.buffer[end-1] == '\r' {
+        end--
+    }
+    return Line{Text: string(o.buffer[m.start:end]), Time: m.time}
+}
+
+// Lines returns all completed lines, oldest first.
+// Output after the last line feed is not included.
+func (o *Output) Lines() []Line {
+    lines := make([]Line, len(o.lines))
+    for i := range o.lines {
+        lines[i] = o.Line(i)
+    }
+    return lines
+}
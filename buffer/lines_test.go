@@ -0,0 +1,44 @@
+package buffer
+
+import "testing"
+
+func TestLines(t *testing.T) {
+    tests := []struct {
+        name string
+        in   []string
+        want []string
+    }{
+        {"lf", []string{"hello\nwor", "ld\n\npartial"}, []string{"hello", "world", ""}},
+        {"crlf", []string{"hello\r\nworld\r", "\n\r\n"}, []string{"hello", "world", ""}},
+        {"one cr stripped", []string{"a\r\r\n"}, []string{"a\r"}},
+        {"no lines", []string{"partial"}, nil},
+        {"lf in osc", []string{"a\x1b]0;x\ny\x07b\n"}, []string{"a\x1b]0;x\ny\x07b"}},
+        {"lf in dcs", []string{"\x1bP$q\n\x1b\\c\n"}, []string{"\x1bP$q\n\x1b\\c"}},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            o := NewOutputBuffer(0)
+            for _, in := range tt.in {
+                o.Write([]byte(in))
+            }
+            lines := o.Lines()
+            if len(lines) != len(tt.want) || o.LineCount() != len(tt.want) {
+                t.Fatalf("got %d lines, want %d", len(lines), len(tt.want))
+            }
+            for i, l := range lines {
+                if l.Text != tt.want[i] {
+                    t.Errorf("line %d = %q, want %q", i, l.Text, tt.want[i])
+                }
+                if l != o.Line(i) {
+                    t.Errorf("Line(%d) = %v, want %v", i, o.Line(i), l)
+                }
+                if l.Time.IsZero() {
+                    t.Errorf("line %d has no time", i)
+                }
+                if i > 0 && l.Time.Before(lines[i-1].Time) {
+                    t.Errorf("line %d is older than line %d", i, i-1)
+                }
+            }
+        })
+    }
+}
@@ -0,0 +1,102 @@
+package buffer
+
+// private (DEC) modes known to the buffer
+const (
+    ModeCursorKeys    = 1
+    ModeAutoWrap      = 7
+    ModeCursorVisible = 25
+    ModeMouseClick    = 1000
+    ModeMouseDrag     = 1002
+    ModeMouseMotion   = 1003
+    ModeFocusEvents   = 1004
+    ModeMouseSGR      = 1006
+    ModeAltScreen     = 1049
+    ModeBracketPaste  = 2004
+)
+
+// ANSI modes known to the buffer.
+// They are only tracked: the bytes stored by Write are never changed,
+// it is up to whatever lays the output out into cells to apply them.
+const (
+    // ModeInsert (IRM) inserts printed characters instead of replacing
+    ModeInsert = 4
+    // ModeNewLine (LNM) makes a line feed also return the carriage
+    ModeNewLine = 20
+)
+
+// defaultANSIModes returns the ANSI modes in their power-on state.
+func defaultANSIModes() map[int]bool {
+    return map[int]bool{
+        ModeInsert:  false,
+        ModeNewLine: false,
+    }
+}
+
+// defaultModes returns the private modes in their power-on state.
+func defaultModes() map[int]bool {
+    return map[int]bool{
+        ModeCursorKeys:    false,
+        ModeAutoWrap:      true,
+        ModeCursorVisible: true,
+        ModeMouseClick:    false,
+        ModeMouseDrag:     false,
+        ModeMouseMotion:   false,
+        ModeFocusEvents:   false,
+        ModeMouseSGR:      false,
+        ModeAltScreen:     false,
+        ModeBracketPaste:  false,
+    }
+}
+
+// Mode reports whether the given private mode is set.
+func (o *Output) Mode(mode int) bool {
+    return o.modes[mode]
+}
+
+// ANSIMode reports whether the given ANSI mode is set.
+func (o *Output) ANSIMode(mode int) bool {
+    return o.ansiModes[mode]
+}
+
+// setANSIMode sets an ANSI mode if it is known.
+func (o *Output) setANSIMode(mode int, on bool) {
+    if _, ok := o.ansiModes[mode]; ok {
+        o.ansiModes[mode] = on
+    }
+}
+
+// setPrivateMode sets a private mode if it is known.
+func (o *Output) setPrivateMode(mode int, on bool) {
+    if _, ok := o.modes[mode]; ok {
+        o.modes[mode] = on
+    }
+}
+
+// setMargins sets the top and bottom scroll margins (1-based).
+// Missing or invalid margins reset the region to the full screen.
+func (o *Output) setMargins(p []int) {
+    top, bottom := 0, 0
+    if len(p) > 0 {
+        top = p[0]
+    }
+    if len(p) > 1 {
+        bottom = p[1]
+    }
+    if top < 1 {
+        top = 1
+    }
+    if bottom < 1 || (o.rows > 0 && bottom > o.rows) {
+        bottom = o.rows
+    }
+    if bottom != 0 && top >= bottom {
+        return
+    }
+    o.top, o.bottom = top, bottom
+}
+
+// Resize sets the size of the screen in cells.
+// The scroll region is reset to the full screen.
+func (o *Output) Resize(rows, cols int) {
+    o.rows, o.cols = rows, cols
+    o.top, o.bottom = 1, rows
+}
@@ -0,0 +1,40 @@
+package buffer
+
+import "testing"
+
+func TestPrivateModes(t *testing.T) {
+    o := NewOutputBuffer(0)
+    o.Write([]byte("\x1b[?2004;1004h\x1b[?25l\x1b[?9999h"))
+    if !o.Mode(ModeBracketPaste) || !o.Mode(ModeFocusEvents) {
+        t.Error("private modes not set")
+    }
+    if o.Mode(ModeCursorVisible) {
+        t.Error("cursor still visible")
+    }
+    if !o.Mode(ModeAutoWrap) {
+        t.Error("autowrap not on by default")
+    }
+    if o.Mode(9999) {
+        t.Error("unknown mode set")
+    }
+}
+
+func TestANSIModes(t *testing.T) {
+    o := NewOutputBuffer(0)
+    o.Write([]byte("\x1b[4;20h"))
+    if !o.ANSIMode(ModeInsert) || !o.ANSIMode(ModeNewLine) {
+        t.Error("ANSI modes not set")
+    }
+    o.Write([]byte("\x1b[4l"))
+    if o.ANSIMode(ModeInsert) {
+        t.Error("insert mode not reset")
+    }
+}
+
+func TestNewLineModeKeepsBytes(t *testing.T) {
+    o := NewOutputBuffer(0)
+    o.Write([]byte("\x1b[20ha\nb"))
+    if got, want := string(o.buffer), "\x1b[20ha\nb"; got != want {
+        t.Errorf("stored %q, want %q", got, want)
+    }
+}
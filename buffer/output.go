@@ -1,9 +1,35 @@
 package buffer
 
+import (
+    "io"
+    "time"
+)
+
 // type that implements the output interface
 type Output struct {
     buffer []byte
     offset int
+    // encoding of data passed to Write, see SetEncoding
+    encoding Encoding
+    // lines marks each completed line, lineStart is where the next starts
+    lines     []lineMark
+    lineStart int
+
+    // handler receives events, see SetHandler
+    handler Handler
+    // responder receives replies to queries, see SetResponder
+    responder io.Writer
+    // parser state
+    state int
+    seq   []byte
+    str   byte
+    // terminal state reported back by queries
+    modes       map[int]bool
+    ansiModes   map[int]bool
+    sgr         sgrState
+    top, bottom int
+    rows, cols  int
+    cursorStyle int
 }
 
 // OutputBuffer is a buffer that can be written to.
@@ -12,12 +38,25 @@ type Output struct {
 
 func NewOutputBuffer(size int) *Output {
     return &Output{
-        buffer: make([]byte, size),
-        offset: 0,
+        buffer:    make([]byte, 0, size),
+        offset:    0,
+        modes:     defaultModes(),
+        ansiModes: defaultANSIModes(),
     }
 }
 
 // Write writes the given byte slice to the buffer.
+// Data is translated to UTF-8 first if another encoding is set.
+// Only a line feed outside escape sequences completes a line.
 func (o *Output) Write(b []byte) {
-    o.buffer = append(o.buffer, b...)
+    b = o.decode(b)
+    for _, c := range b {
+        o.buffer = append(o.buffer, c)
+        if c == '\n' && o.state == stateGround {
+            end := len(o.buffer) - 1
+            o.lines = append(o.lines, lineMark{start: o.lineStart, end: end, time: time.Now()})
+            o.lineStart = len(o.buffer)
+        }
+        o.parseByte(c)
+    }
 }
@@ -0,0 +1,214 @@
+package buffer
+
+import (
+    "encoding/base64"
+    "strconv"
+    "strings"
+)
+
+// parser states
+const (
+    stateGround = iota
+    stateEscape
+    stateCSI
+    stateString
+    stateStringEscape
+)
+
+// maxSeqLen caps how much of a single sequence is kept.
+const maxSeqLen = 1 << 20
+
+// parseByte advances the parser by a single byte.
+// State is kept between calls so sequences can be split across writes.
+func (o *Output) parseByte(c byte) {
+    switch o.state {
+    case stateGround:
+        if c == 0x1b {
+            o.state = stateEscape
+            return
+        }
+        o.execute(c)
+    case stateEscape:
+        o.seq = o.seq[:0]
+        switch c {
+        case '[':
+            o.state = stateCSI
+        case ']', 'P', 'X', '^', '_':
+            // OSC, DCS, SOS, PM and APC are all control strings,
+            // only OSC and DCS payloads are used
+            o.str = c
+            o.state = stateString
+        case 'c':
+            o.Reset()
+            o.state = stateGround
+        default:
+            o.state = stateGround
+        }
+    case stateCSI:
+        switch {
+        case c == 0x1b:
+            // ESC cancels the sequence and starts a new one
+            o.state = stateEscape
+        case c == 0x18 || c == 0x1a:
+            // CAN and SUB cancel the sequence
+            o.state = stateGround
+        case c < 0x20:
+            // other C0 controls run as if the sequence wasn't there
+            o.execute(c)
+        case c >= 0x40 && c <= 0x7e:
+            o.dispatchCSI(string(o.seq), c)
+            o.state = stateGround
+        case c != 0x7f:
+            o.appendSeq(c)
+        }
+    case stateString:
+        switch c {
+        case 0x18, 0x1a:
+            o.state = stateGround
+        case 0x07:
+            // SOS, PM and APC only end at ST, BEL in them is ignored
+            if o.str == ']' || o.str == 'P' {
+                o.dispatchString(string(o.seq))
+                o.state = stateGround
+            }
+        case 0x1b:
+            o.state = stateStringEscape
+        default:
+            if o.str == ']' || o.str == 'P' {
+                o.appendSeq(c)
+            }
+        }
+    case stateStringEscape:
+        if c == '\\' {
+            o.dispatchString(string(o.seq))
+            o.state = stateGround
+            return
+        }
+        // not a string terminator, drop the string and start over
+        o.state = stateEscape
+        o.parseByte(c)
+    }
+}
+
+// execute runs a C0 control character.
+func (o *Output) execute(c byte) {
+    if c == 0x07 && o.handler != nil {
+        o.handler.Bell()
+    }
+}
+
+// appendSeq adds a byte to the current sequence.
+func (o *Output) appendSeq(c byte) {
+    if len(o.seq) < maxSeqLen {
+        o.seq = append(o.seq, c)
+    }
+}
+
+// dispatchCSI handles a complete CSI sequence.
+func (o *Output) dispatchCSI(seq string, final byte) {
+    prefix, params, inter := splitCSI(seq)
+    switch {
+    case prefix == 0 && inter == "" && final == 't':
+        p := splitParams(params)
+        if len(p) == 3 && p[0] == 8 && o.handler != nil {
+            o.handler.ResizeRequest(p[1], p[2])
+        }
+    case prefix == 0 && inter == "" && final == 'm':
+        o.setSGR(params)
+    case prefix == 0 && inter == "" && final == 'r':
+        o.setMargins(splitParams(params))
+    case prefix == 0 && inter == " " && final == 'q':
+        p := splitParams(params)
+        o.cursorStyle = 0
+        if len(p) > 0 {
+            o.cursorStyle = p[0]
+        }
+    case prefix == 0 && inter == "" && (final == 'h' || final == 'l'):
+        for _, m := range splitParams(params) {
+            o.setANSIMode(m, final == 'h')
+        }
+    case prefix == '?' && inter == "" && (final == 'h' || final == 'l'):
+        for _, m := range splitParams(params) {
+            o.setPrivateMode(m, final == 'h')
+        }
+    case prefix == 0 && inter == "!" && final == 'p':
+        o.SoftReset()
+    case (prefix == 0 || prefix == '?') && inter == "$" && final == 'p':
+        p := splitParams(params)
+        if len(p) == 1 {
+            o.reportMode(p[0], prefix == '?')
+        }
+    }
+}
+
+// dispatchString handles a complete OSC or DCS string.
+// SOS, PM and APC strings are dropped.
+func (o *Output) dispatchString(s string) {
+    switch o.str {
+    case ']':
+        o.dispatchOSC(s)
+    case 'P':
+        o.dispatchDCS(s)
+    }
+}
+
+// dispatchDCS handles a complete DCS string.
+func (o *Output) dispatchDCS(s string) {
+    if q, ok := strings.CutPrefix(s, "$q"); ok {
+        o.reportSetting(q)
+    }
+}
+
+// dispatchOSC handles a complete OSC sequence.
+func (o *Output) dispatchOSC(s string) {
+    if o.handler == nil {
+        return
+    }
+    cmd, arg, _ := strings.Cut(s, ";")
+    switch cmd {
+    case "0", "2":
+        o.handler.TitleChanged(arg)
+    case "7":
+        o.handler.CwdChanged(arg)
+    case "52":
+        sel, data, ok := strings.Cut(arg, ";")
+        if !ok || data == "?" {
+            // clipboard reads are not supported
+            return
+        }
+        decoded, err := base64.StdEncoding.DecodeString(data)
+        if err != nil {
+            return
+        }
+        o.handler.ClipboardWrite(sel, decoded)
+    }
+}
+
+// splitCSI splits a CSI sequence into its private prefix byte,
+// parameter string and trailing intermediate bytes.
+func splitCSI(seq string) (byte, string, string) {
+    var prefix byte
+    if seq != "" && seq[0] >= 0x3c && seq[0] <= 0x3f {
+        prefix = seq[0]
+        seq = seq[1:]
+    }
+    i := len(seq)
+    for i > 0 && seq[i-1] >= 0x20 && seq[i-1] <= 0x2f {
+        i--
+    }
+    return prefix, seq[:i], seq[i:]
+}
+
+// splitParams parses semicolon separated numeric parameters.
+// Missing or invalid parameters are returned as 0.
+func splitParams(s string) []int {
+    if s == "" {
+        return nil
+    }
+    fields := strings.Split(s, ";")
+    p := make([]int, len(fields))
+    for i, f := range fields {
+        p[i], _ = strconv.Atoi(f)
+    }
+    return p
+}
@@ -0,0 +1,88 @@
+package buffer
+
+import (
+    "fmt"
+    "reflect"
+    "testing"
+)
+
+// recorder is a Handler that records the events it receives.
+type recorder struct {
+    events []string
+}
+
+func (r *recorder) Bell() {
+    r.events = append(r.events, "bell")
+}
+
+func (r *recorder) TitleChanged(title string) {
+    r.events = append(r.events, "title "+title)
+}
+
+func (r *recorder) ClipboardWrite(selection string, data []byte) {
+    r.events = append(r.events, "clipboard "+selection+" "+string(data))
+}
+
+func (r *recorder) CwdChanged(uri string) {
+    r.events = append(r.events, "cwd "+uri)
+}
+
+func (r *recorder) ResizeRequest(rows, cols int) {
+    r.events = append(r.events, fmt.Sprintf("resize %d %d", rows, cols))
+}
+
+func TestHandlerEvents(t *testing.T) {
+    tests := []struct {
+        name string
+        in   string
+        want []string
+    }{
+        {"bell", "a\x07b", []string{"bell"}},
+        {"title bel", "\x1b]0;one\x07", []string{"title one"}},
+        {"title st", "\x1b]2;two\x1b\\", []string{"title two"}},
+        {"clipboard", "\x1b]52;c;aGVsbG8=\x07", []string{"clipboard c hello"}},
+        {"clipboard query", "\x1b]52;c;?\x07", nil},
+        {"clipboard bad base64", "\x1b]52;c;!!\x07", nil},
+        {"cwd", "\x1b]7;file:///tmp\x07", []string{"cwd file:///tmp"}},
+        {"resize", "\x1b[8;24;80t", []string{"resize 24 80"}},
+        {"other window op", "\x1b[22;0t", nil},
+        {"esc aborts osc", "\x1b]2;x\x1b]2;y\x07", []string{"title y"}},
+        {"esc cancels csi", "\x1b[\x1b]0;title\x07", []string{"title title"}},
+        {"can cancels csi", "\x1b[1\x18\x1b]2;t2\x07", []string{"title t2"}},
+        {"sub cancels csi", "\x1b[1\x1a\x07", []string{"bell"}},
+        {"bel inside csi", "\x1b[8;\x0724;80t", []string{"bell", "resize 24 80"}},
+        {"del inside csi", "\x1b[8;24\x7f;80t", []string{"resize 24 80"}},
+        {"can cancels osc", "\x1b]2;x\x18\x07", []string{"bell"}},
+        {"apc dropped", "\x1b_G\x07a=T\x1b\\", nil},
+        {"pm dropped", "\x1b^x\x07y\x1b\\\x07", []string{"bell"}},
+        {"sos dropped", "\x1bXpayload\x07", nil},
+        {"esc aborts apc", "\x1b_x\x1b]0;y\x07", []string{"title y"}},
+        {"apc then title", "\x1b_x\x1b\\\x1b]0;t\x07", []string{"title t"}},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            r := &recorder{}
+            o := NewOutputBuffer(0)
+            o.SetHandler(r)
+            o.Write([]byte(tt.in))
+            if !reflect.DeepEqual(r.events, tt.want) {
+                t.Errorf("events = %q, want %q", r.events, tt.want)
+            }
+        })
+    }
+}
+
+func TestSplitWrites(t *testing.T) {
+    in := "\x1b]0;split title\x07\x1b[8;24;80t\x1b]52;c;aGVsbG8=\x1b\\"
+    want := []string{"title split title", "resize 24 80", "clipboard c hello"}
+    for i := 1; i < len(in); i++ {
+        r := &recorder{}
+        o := NewOutputBuffer(0)
+        o.SetHandler(r)
+        o.Write([]byte(in[:i]))
+        o.Write([]byte(in[i:]))
+        if !reflect.DeepEqual(r.events, want) {
+            t.Errorf("split at %d: events = %q, want %q", i, r.events, want)
+        }
+    }
+}
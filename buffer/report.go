@@ -0,0 +1,65 @@
+package buffer
+
+import (
+    "fmt"
+    "io"
+    "strconv"
+)
+
+// DECRQM mode states
+const (
+    modeNotRecognized = 0
+    modeSet           = 1
+    modeReset         = 2
+)
+
+// SetResponder sets where replies to queries are written,
+// usually the PTY the output came from. A nil writer drops them.
+func (o *Output) SetResponder(w io.Writer) {
+    o.responder = w
+}
+
+// reply writes a response back to the application.
+func (o *Output) reply(s string) {
+    if o.responder != nil {
+        io.WriteString(o.responder, s)
+    }
+}
+
+// reportMode answers DECRQM (CSI ? Ps $ p and CSI Ps $ p).
+func (o *Output) reportMode(mode int, private bool) {
+    modes, prefix := o.ansiModes, ""
+    if private {
+        modes, prefix = o.modes, "?"
+    }
+    state := modeNotRecognized
+    if on, ok := modes[mode]; ok {
+        state = modeReset
+        if on {
+            state = modeSet
+        }
+    }
+    o.reply(fmt.Sprintf("\033[%s%d;%d$y", prefix, mode, state))
+}
+
+// reportSetting answers DECRQSS (DCS $ q Pt ST).
+func (o *Output) reportSetting(q string) {
+    var value string
+    switch q {
+    case "m":
+        value = o.sgr.String() + "m"
+    case "r":
+        if o.bottom == 0 {
+            // screen size unknown
+            o.reply("\033P0$r\033\\")
+            return
+        }
+        value = strconv.Itoa(o.top) + ";" + strconv.Itoa(o.bottom) + "r"
+    case " q":
+        value = strconv.Itoa(o.cursorStyle) + " q"
+    default:
+        o.reply("\033P0$r\033\\")
+        return
+    }
+    o.reply("\033P1$r" + value + "\033\\")
+}
@@ -0,0 +1,69 @@
+package buffer
+
+import (
+    "bytes"
+    "testing"
+)
+
+// query writes in to a fresh buffer of the given size and returns the replies.
+func query(rows, cols int, in string) string {
+    var w bytes.Buffer
+    o := NewOutputBuffer(0)
+    o.SetResponder(&w)
+    if rows > 0 {
+        o.Resize(rows, cols)
+    }
+    o.Write([]byte(in))
+    return w.String()
+}
+
+func TestReportMode(t *testing.T) {
+    tests := []struct {
+        name string
+        in   string
+        want string
+    }{
+        {"default set", "\x1b[?25$p", "\x1b[?25;1$y"},
+        {"default reset", "\x1b[?2004$p", "\x1b[?2004;2$y"},
+        {"after set", "\x1b[?2004h\x1b[?2004$p", "\x1b[?2004;1$y"},
+        {"after reset", "\x1b[?7l\x1b[?7$p", "\x1b[?7;2$y"},
+        {"unknown private", "\x1b[?9999$p", "\x1b[?9999;0$y"},
+        {"ansi reset", "\x1b[4$p", "\x1b[4;2$y"},
+        {"ansi set", "\x1b[20h\x1b[20$p", "\x1b[20;1$y"},
+        {"unknown ansi", "\x1b[3$p", "\x1b[3;0$y"},
+        {"other prefix", "\x1b[>4$p", ""},
+        {"equals prefix", "\x1b[=1$p", ""},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := query(0, 0, tt.in); got != tt.want {
+                t.Errorf("reply = %q, want %q", got, tt.want)
+            }
+        })
+    }
+}
+
+func TestReportSetting(t *testing.T) {
+    tests := []struct {
+        name string
+        rows int
+        in   string
+        want string
+    }{
+        {"sgr default", 0, "\x1bP$qm\x1b\\", "\x1bP1$r0m\x1b\\"},
+        {"sgr set", 0, "\x1b[1;4:3;31;48;5;17m\x1bP$qm\x1b\\", "\x1bP1$r0;1;4:3;31;48;5;17m\x1b\\"},
+        {"margins unknown size", 0, "\x1bP$qr\x1b\\", "\x1bP0$r\x1b\\"},
+        {"margins default", 24, "\x1bP$qr\x1b\\", "\x1bP1$r1;24r\x1b\\"},
+        {"margins set", 24, "\x1b[3;20r\x1bP$qr\x1b\\", "\x1bP1$r3;20r\x1b\\"},
+        {"margins invalid", 24, "\x1b[20;3r\x1bP$qr\x1b\\", "\x1bP1$r1;24r\x1b\\"},
+        {"cursor style", 0, "\x1b[5 q\x1bP$q q\x1b\\", "\x1bP1$r5 q\x1b\\"},
+        {"unknown", 0, "\x1bP$qzz\x07", "\x1bP0$r\x1b\\"},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := query(tt.rows, 80, tt.in); got != tt.want {
+                t.Errorf("reply = %q, want %q", got, tt.want)
+            }
+        })
+    }
+}
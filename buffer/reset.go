@@ -0,0 +1,23 @@
+package buffer
+
+// Reset performs a full terminal reset (RIS, ESC c).
+// All modes, margins and graphic rendition return to their power-on
+// state. The buffer contents are kept.
+func (o *Output) Reset() {
+    o.modes = defaultModes()
+    o.ansiModes = defaultANSIModes()
+    o.sgr = sgrState{}
+    o.top, o.bottom = 1, o.rows
+    o.cursorStyle = 0
+}
+
+// SoftReset performs a soft terminal reset (DECSTR, CSI ! p).
+// Unlike Reset it leaves mouse, paste and screen modes alone.
+func (o *Output) SoftReset() {
+    o.setPrivateMode(ModeCursorKeys, false)
+    o.setPrivateMode(ModeAutoWrap, false)
+    o.setPrivateMode(ModeCursorVisible, true)
+    o.setANSIMode(ModeInsert, false)
+    o.sgr = sgrState{}
+    o.top, o.bottom = 1, o.rows
+}
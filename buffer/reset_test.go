@@ -0,0 +1,39 @@
+package buffer
+
+import "testing"
+
+func TestReset(t *testing.T) {
+    setup := "\x1b[?2004h\x1b[?1h\x1b[4h\x1b[1m\x1b[2;5r\x1b[3 q"
+    tests := []struct {
+        name  string
+        reset string
+        in    string
+        want  string
+    }{
+        {"soft keeps paste", "\x1b[!p", "\x1b[?2004$p", "\x1b[?2004;1$y"},
+        {"soft cursor keys", "\x1b[!p", "\x1b[?1$p", "\x1b[?1;2$y"},
+        {"soft autowrap", "\x1b[!p", "\x1b[?7$p", "\x1b[?7;2$y"},
+        {"soft insert", "\x1b[!p", "\x1b[4$p", "\x1b[4;2$y"},
+        {"soft sgr", "\x1b[!p", "\x1bP$qm\x1b\\", "\x1bP1$r0m\x1b\\"},
+        {"soft margins", "\x1b[!p", "\x1bP$qr\x1b\\", "\x1bP1$r1;24r\x1b\\"},
+        {"full paste", "\x1bc", "\x1b[?2004$p", "\x1b[?2004;2$y"},
+        {"full autowrap", "\x1bc", "\x1b[?7$p", "\x1b[?7;1$y"},
+        {"full cursor style", "\x1bc", "\x1bP$q q\x1b\\", "\x1bP1$r0 q\x1b\\"},
+        {"full margins", "\x1bc", "\x1bP$qr\x1b\\", "\x1bP1$r1;24r\x1b\\"},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := query(24, 80, setup+tt.reset+tt.in); got != tt.want {
+                t.Errorf("reply = %q, want %q", got, tt.want)
+            }
+        })
+    }
+}
+
+func TestResetZeroOutput(t *testing.T) {
+    var o Output
+    o.Write([]byte("\x1b[!p\x1bc"))
+    if !o.Mode(ModeAutoWrap) {
+        t.Error("full reset didn't restore autowrap")
+    }
+}
@@ -0,0 +1,117 @@
+package buffer
+
+import "strings"
+
+// sgr attribute slots
+const (
+    sgrBold = iota
+    sgrDim
+    sgrItalic
+    sgrUnderline
+    sgrBlink
+    sgrInverse
+    sgrHidden
+    sgrStrike
+    sgrFg
+    sgrBg
+    sgrUnderlineColor
+    sgrSlots
+)
+
+// sgrState is the graphic rendition in effect.
+// Each slot holds the parameter that set it, or "" when off/default,
+// so the state stays bounded however many SGR sequences arrive.
+type sgrState [sgrSlots]string
+
+// String returns the state as SGR parameters, starting with a reset.
+func (s sgrState) String() string {
+    p := []string{"0"}
+    for _, v := range s {
+        if v != "" {
+            p = append(p, v)
+        }
+    }
+    return strings.Join(p, ";")
+}
+
+// setSGR applies the parameters of an SGR sequence (CSI ... m).
+func (o *Output) setSGR(params string) {
+    p := strings.Split(params, ";")
+    for i := 0; i < len(p); i++ {
+        // colon sub-parameters (4:3, 38:2::r:g:b) stay with their code
+        code, _, _ := strings.Cut(p[i], ":")
+        switch code {
+        case "", "0":
+            o.sgr = sgrState{}
+        case "1":
+            o.sgr[sgrBold] = p[i]
+        case "2":
+            o.sgr[sgrDim] = p[i]
+        case "3":
+            o.sgr[sgrItalic] = p[i]
+        case "4", "21":
+            o.sgr[sgrUnderline] = p[i]
+            if p[i] == "4:0" {
+                o.sgr[sgrUnderline] = ""
+            }
+        case "5", "6":
+            o.sgr[sgrBlink] = p[i]
+        case "7":
+            o.sgr[sgrInverse] = p[i]
+        case "8":
+            o.sgr[sgrHidden] = p[i]
+        case "9":
+            o.sgr[sgrStrike] = p[i]
+        case "22":
+            o.sgr[sgrBold], o.sgr[sgrDim] = "", ""
+        case "23":
+            o.sgr[sgrItalic] = ""
+        case "24":
+            o.sgr[sgrUnderline] = ""
+        case "25":
+            o.sgr[sgrBlink] = ""
+        case "27":
+            o.sgr[sgrInverse] = ""
+        case "28":
+            o.sgr[sgrHidden] = ""
+        case "29":
+            o.sgr[sgrStrike] = ""
+        case "30", "31", "32", "33", "34", "35", "36", "37",
+            "90", "91", "92", "93", "94", "95", "96", "97":
+            o.sgr[sgrFg] = p[i]
+        case "40", "41", "42", "43", "44", "45", "46", "47",
+            "100", "101", "102", "103", "104", "105", "106", "107":
+            o.sgr[sgrBg] = p[i]
+        case "39":
+            o.sgr[sgrFg] = ""
+        case "49":
+            o.sgr[sgrBg] = ""
+        case "59":
+            o.sgr[sgrUnderlineColor] = ""
+        case "38", "48", "58":
+            // extended colors carry their own arguments
+            n := 1
+            if code == p[i] && i+1 < len(p) {
+                switch p[i+1] {
+                case "5":
+                    n = 3
+                case "2":
+                    n = 5
+                }
+            }
+            if i+n > len(p) {
+                n = len(p) - i
+            }
+            v := strings.Join(p[i:i+n], ";")
+            switch code {
+            case "38":
+                o.sgr[sgrFg] = v
+            case "48":
+                o.sgr[sgrBg] = v
+            case "58":
+                o.sgr[sgrUnderlineColor] = v
+            }
+            i += n - 1
+        }
+    }
+}
@@ -0,0 +1,45 @@
+package buffer
+
+import (
+    "strings"
+    "testing"
+)
+
+func TestSGR(t *testing.T) {
+    tests := []struct {
+        name string
+        in   string
+        want string
+    }{
+        {"empty", "", "0"},
+        {"attributes", "\x1b[1;2;3;4;5;7;8;9m", "0;1;2;3;4;5;7;8;9"},
+        {"reset", "\x1b[1;31m\x1b[m", "0"},
+        {"reset in list", "\x1b[1;31;0;4m", "0;4"},
+        {"clear slots", "\x1b[1;3;4;7;31;44;58;5;1m\x1b[22;23;24;27;39;49;59m", "0"},
+        {"replace color", "\x1b[31m\x1b[32m", "0;32"},
+        {"bright colors", "\x1b[91;103m", "0;91;103"},
+        {"256 colors", "\x1b[38;5;0;48;5;17m", "0;38;5;0;48;5;17"},
+        {"truecolor", "\x1b[38;2;1;2;3m", "0;38;2;1;2;3"},
+        {"colon color", "\x1b[58:2::1:2:3m", "0;58:2::1:2:3"},
+        {"underline style", "\x1b[4:3m", "0;4:3"},
+        {"underline off", "\x1b[4:3m\x1b[4:0m", "0"},
+        {"truncated color", "\x1b[38;5m", "0;38;5"},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            o := NewOutputBuffer(0)
+            o.Write([]byte(tt.in))
+            if got := o.sgr.String(); got != tt.want {
+                t.Errorf("sgr = %q, want %q", got, tt.want)
+            }
+        })
+    }
+}
+
+func TestSGRBounded(t *testing.T) {
+    o := NewOutputBuffer(0)
+    o.Write([]byte(strings.Repeat("\x1b[1m\x1b[22m\x1b[31m\x1b[39m", 1000) + "\x1b[1m"))
+    if got, want := o.sgr.String(), "0;1"; got != want {
+        t.Errorf("sgr = %q, want %q", got, want)
+    }
+}
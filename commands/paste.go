@@ -0,0 +1,64 @@
+package commands
+
+import (
+    "strings"
+    "unicode/utf8"
+)
+
+// markers wrapped around pasted text in bracketed paste mode
+const (
+    PasteStart = "\033[200~"
+    PasteEnd   = "\033[201~"
+)
+
+// function to strip dangerous control characters from pasted text
+// invalid UTF-8 bytes are kept as they are, except raw bytes in the C1
+// range (0x80-0x9f) which some terminals read as 8-bit controls
+func SanitizePaste(data string) string {
+    var b strings.Builder
+    b.Grow(len(data))
+    for i := 0; i < len(data); {
+        r, size := utf8.DecodeRuneInString(data[i:])
+        switch {
+        case r == utf8.RuneError && size == 1:
+            if data[i] > 0x9f {
+                b.WriteByte(data[i])
+            }
+        case r == '\t' || r == '\n' || r == '\r':
+            // keep whitespace the shell expects
+            b.WriteRune(r)
+        case r < 0x20 || r == 0x7f:
+            // drop C0 controls, ESC included, so pasted text can't end
+            // bracketed paste early or inject its own sequences
+        case r >= 0x80 && r <= 0x9f:
+            // drop C1 controls
+        default:
+            b.WriteString(data[i : i+size])
+        }
+        i += size
+    }
+    return b.String()
+}
+
+// function to check if pasted text should be confirmed before sending
+// without bracketed paste any CR or LF runs a command right away, with
+// it a single trailing newline is fine but multi-line text still asks
+func NeedsPasteConfirm(data string, bracketed bool) bool {
+    if bracketed {
+        data = strings.TrimSuffix(data, "\n")
+        data = strings.TrimSuffix(data, "\r")
+    }
+    if strings.ContainsAny(data, "\r\n") {
+        return true
+    }
+    return SanitizePaste(data) != data
+}
+
+// function to prepare pasted text to be sent to the shell
+func Paste(data string, bracketed bool) string {
+    data = SanitizePaste(data)
+    if bracketed {
+        return PasteStart + data + PasteEnd
+    }
+    return data
+}
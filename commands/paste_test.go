@@ -0,0 +1,67 @@
+package commands
+
+import "testing"
+
+func TestSanitizePaste(t *testing.T) {
+    tests := []struct {
+        name string
+        in   string
+        want string
+    }{
+        {"plain", "ls -la", "ls -la"},
+        {"whitespace", "a\tb\r\nc\n", "a\tb\r\nc\n"},
+        {"c0", "a\x00b\x07c\x08d", "abcd"},
+        {"del", "a\x7fb", "ab"},
+        {"esc", "a\x1b[31mb", "a[31mb"},
+        {"paste end", "echo hi\x1b[201~; rm -rf ~\n", "echo hi[201~; rm -rf ~\n"},
+        {"c1", "a\u009b31mb\u0085c", "a31mbc"},
+        {"utf-8", "héllo 世界 🙂", "héllo 世界 🙂"},
+        {"invalid utf-8", "a\xffb\xe9c", "a\xffb\xe9c"},
+        {"raw c1 byte", "a\x9b31mb", "a31mb"},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := SanitizePaste(tt.in); got != tt.want {
+                t.Errorf("SanitizePaste(%q) = %q, want %q", tt.in, got, tt.want)
+            }
+        })
+    }
+}
+
+func TestNeedsPasteConfirm(t *testing.T) {
+    tests := []struct {
+        name      string
+        in        string
+        bracketed bool
+        want      bool
+    }{
+        {"plain", "ls -la", false, false},
+        {"plain bracketed", "ls -la", true, false},
+        {"trailing lf", "rm -rf ~\n", false, true},
+        {"trailing cr", "echo hi\r", false, true},
+        {"trailing crlf", "echo hi\r\n", false, true},
+        {"trailing lf bracketed", "rm -rf ~\n", true, false},
+        {"trailing crlf bracketed", "echo hi\r\n", true, false},
+        {"two trailing lf bracketed", "echo hi\n\n", true, true},
+        {"multi-line bracketed", "a\nb", true, true},
+        {"paste end bracketed", "echo hi\x1b[201~rm -rf ~", true, true},
+        {"c1", "a\u009bb", false, true},
+        {"tab", "a\tb", false, false},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := NeedsPasteConfirm(tt.in, tt.bracketed); got != tt.want {
+                t.Errorf("NeedsPasteConfirm(%q, %v) = %v, want %v", tt.in, tt.bracketed, got, tt.want)
+            }
+        })
+    }
+}
+
+func TestPaste(t *testing.T) {
+    if got, want := Paste("a\x1b[201~b", true), PasteStart+"a[201~b"+PasteEnd; got != want {
+        t.Errorf("Paste bracketed = %q, want %q", got, want)
+    }
+    if got, want := Paste("a\x07b\n", false), "ab\n"; got != want {
+        t.Errorf("Paste = %q, want %q", got, want)
+    }
+}